@@ -1,95 +1,251 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
 
+	"periph.io/x/conn/v3/gpio"
+
 	"github.com/snehithgit/pi_oled_info/internal/display"
-	"github.com/snehithgit/pi_oled_info/internal/sysinfo/cpu"
-	"github.com/snehithgit/pi_oled_info/internal/sysinfo/disk"
-	"github.com/snehithgit/pi_oled_info/internal/sysinfo/ipaddr"
-	"github.com/snehithgit/pi_oled_info/internal/sysinfo/uptime"
+	"github.com/snehithgit/pi_oled_info/internal/fan"
+	"github.com/snehithgit/pi_oled_info/internal/input"
+	"github.com/snehithgit/pi_oled_info/internal/layout"
+	"github.com/snehithgit/pi_oled_info/internal/sysinfo/registry"
+
+	// Blank-imported so their init() self-registers with the registry; the
+	// layout spec resolves providers by name, not by direct reference.
+	_ "github.com/snehithgit/pi_oled_info/internal/sysinfo/cpu"
+	_ "github.com/snehithgit/pi_oled_info/internal/sysinfo/disk"
+	_ "github.com/snehithgit/pi_oled_info/internal/sysinfo/ipaddr"
+	_ "github.com/snehithgit/pi_oled_info/internal/sysinfo/temp"
+	_ "github.com/snehithgit/pi_oled_info/internal/sysinfo/uptime"
 )
 
+// layoutPath is where a user-supplied layout spec is looked up, relative to
+// their home directory. See internal/layout for the spec syntax.
+const layoutPath = ".config/pi_oled_info/layout"
+
+// pluginsPath is where out-of-tree provider plugins are loaded from,
+// relative to the user's home directory. See internal/sysinfo/registry.
+const pluginsPath = ".config/pi_oled_info/plugins"
+
+// fanConfigs describes the PWM fan(s) attached to the board. GPIO18 is the
+// Pi's hardware PWM0 pin, the usual choice for a 4-pin fan.
+var fanConfigs = []fan.Config{
+	{
+		Pin:        "GPIO18",
+		MinTempC:   40,
+		MaxTempC:   70,
+		MinDuty:    0,
+		MaxDuty:    gpio.DutyMax,
+		Hysteresis: 3,
+	},
+}
+
+// Page is one screen's worth of layout, cycled through by the page button.
+type Page = []layout.LineSpec
+
 func main() {
 	// Initialize the display
-	disp, err := display.New()
+	disp, err := display.New(display.Config{Kind: display.KindSSD1306})
 	if err != nil {
 		log.Fatalf("Failed to initialize display: %v", err)
 	}
 	defer disp.Close()
 
+	loadPlugins()
+	pages := buildPages(loadLayout())
+	fanCtrl := startFanController()
+
+	events := make(chan input.Event, 4)
+	buttonDone := make(chan struct{})
+	startButton(events, buttonDone)
+
 	// Setup graceful shutdown
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
 	done := make(chan bool, 1)
+	fanDone := make(chan struct{})
 
 	// Run the display update in a goroutine
 	go func() {
 		ticker := time.NewTicker(5 * time.Second)
 		defer ticker.Stop()
 
+		currentPage := 0
+
 		// Initial display update
-		updateDisplay(disp)
+		updateDisplay(disp, pages[currentPage])
 
-		// Loop for periodic updates
 		for {
 			select {
 			case <-ticker.C:
-				updateDisplay(disp)
+				updateDisplay(disp, pages[currentPage])
+			case ev := <-events:
+				switch ev {
+				case input.PageNext:
+					currentPage = (currentPage + 1) % len(pages)
+				case input.PagePrev:
+					currentPage = (currentPage - 1 + len(pages)) % len(pages)
+				case input.PageSelect:
+					if r, ok := disp.(display.FullRefresher); ok {
+						if err := r.FullRefresh(); err != nil {
+							log.Printf("Error forcing full refresh: %v", err)
+						}
+					}
+				}
+				// React immediately rather than waiting for the next tick.
+				updateDisplay(disp, pages[currentPage])
 			case <-done:
 				return
 			}
 		}
 	}()
 
+	// Run the fan control loop in its own goroutine, alongside the display.
+	if fanCtrl != nil {
+		go fanCtrl.Run(fanDone)
+	}
+
 	// Wait for signal
 	<-sigs
 	log.Println("Shutting down...")
 	done <- true
+	close(fanDone)
+	close(buttonDone)
+	if fanCtrl != nil {
+		fanCtrl.Halt()
+	}
 	time.Sleep(250 * time.Millisecond) // Give display time to clean up
 }
 
-func updateDisplay(disp *display.Display) {
-	disp.Clear()
+// buildPages assembles the pages a headless board with just an OLED and a
+// button can cycle through: IP/network, CPU/temp/fan, disk, and finally
+// the user's own layout spec.
+func buildPages(userPage Page) []Page {
+	return []Page{
+		mustParsePage("ip"),
+		mustParsePage("cpu\ntemp\nfan"),
+		mustParsePage("disk"),
+		userPage,
+	}
+}
+
+func mustParsePage(text string) Page {
+	spec, err := layout.Parse(text)
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in page %q: %v", text, err))
+	}
+	return spec
+}
+
+// startButton watches the page button and forwards its events, logging and
+// continuing without button support if no button is wired up.
+func startButton(events chan<- input.Event, stop <-chan struct{}) {
+	if err := input.Watch(input.DefaultConfig, events, stop); err != nil {
+		log.Printf("Error setting up page button: %v", err)
+	}
+}
 
-	// Line 1: IP address
-	ip, err := ipaddr.Get()
+// startFanController sets up the PWM fan controller, returning nil if no
+// fan pin could be resolved (e.g. running off-Pi without one attached).
+func startFanController() *fan.Controller {
+	ctrl, err := fan.NewController(fanConfigs)
 	if err != nil {
-		ip = "IP: Error"
-		log.Printf("Error getting IP address: %v", err)
+		log.Printf("Error setting up fan controller: %v", err)
+		return nil
 	}
-	disp.WriteLine(0, ip)
+	return ctrl
+}
 
-	// Line 2: System uptime
-	upStr, err := uptime.Get()
+// loadPlugins loads any out-of-tree provider plugins from pluginsPath. A
+// missing plugins directory is normal and not logged.
+func loadPlugins() {
+	home, err := os.UserHomeDir()
 	if err != nil {
-		upStr = "Up: Error"
-		log.Printf("Error getting uptime: %v", err)
+		return
 	}
-	disp.WriteLine(1, upStr)
 
-	// Line 3: CPU usage and temperature
-	cpuInfo, err := cpu.Get()
+	if err := registry.LoadPlugins(filepath.Join(home, pluginsPath)); err != nil {
+		log.Printf("Error loading provider plugins: %v", err)
+	}
+}
+
+// loadLayout reads the user's layout spec, falling back to layout.DefaultSpec
+// if it's missing or invalid.
+func loadLayout() []layout.LineSpec {
+	home, err := os.UserHomeDir()
 	if err != nil {
-		cpuInfo = "CPU: Error"
-		log.Printf("Error getting CPU info: %v", err)
+		return layout.DefaultSpec
 	}
-	disp.WriteLine(2, cpuInfo)
 
-	// Line 4: Disk usage
-	diskInfo, err := disk.Get()
+	content, err := os.ReadFile(filepath.Join(home, layoutPath))
 	if err != nil {
-		diskInfo = "Disk: Error"
-		log.Printf("Error getting disk info: %v", err)
+		return layout.DefaultSpec
+	}
+
+	spec, err := layout.Parse(string(content))
+	if err != nil || len(spec) == 0 {
+		log.Printf("Error parsing layout spec, using default: %v", err)
+		return layout.DefaultSpec
+	}
+	return spec
+}
+
+func updateDisplay(disp display.Display, spec []layout.LineSpec) {
+	disp.Clear()
+
+	cols, rows := disp.Size()
+	for i, ls := range spec {
+		if i >= rows {
+			break
+		}
+		disp.WriteLine(i, renderLine(ls, cols))
 	}
-	disp.WriteLine(3, diskInfo)
 
-	// Update the display
 	if err := disp.Update(); err != nil {
 		log.Printf("Error updating display: %v", err)
 	}
 }
+
+// renderLine runs every provider on a line and concatenates their output,
+// each truncated/padded to its share of width.
+func renderLine(ls layout.LineSpec, width int) string {
+	shares := ls.Shares(width)
+
+	line := ""
+	for i, p := range ls {
+		text, err := registry.Get(p.ProviderName)
+		if err != nil {
+			text = fmt.Sprintf("%s: Error", p.ProviderName)
+			log.Printf("Error getting %s: %v", p.ProviderName, err)
+		}
+
+		line += fitField(text, shares[i])
+	}
+	return line
+}
+
+// fitField truncates or space-pads s to exactly width characters.
+func fitField(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if len(s) > width {
+		return s[:width]
+	}
+	return s + spaces(width-len(s))
+}
+
+func spaces(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = ' '
+	}
+	return string(b)
+}
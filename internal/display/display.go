@@ -1,5 +1,8 @@
-// Updated display.go to implement anti-flicker display updates
-
+// Package display renders the sensor lines produced by the sysinfo
+// providers onto a small character-oriented screen. It supports more than
+// one physical panel (SSD1306/I2C OLED, Waveshare-style e-paper/SPI) behind
+// a single Display interface so the rest of the program never has to know
+// which one is attached.
 package display
 
 import (
@@ -12,260 +15,285 @@ import (
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/basicfont"
 	"golang.org/x/image/math/fixed"
-
-	"periph.io/x/conn/v3/i2c"
-	"periph.io/x/conn/v3/i2c/i2creg"
-	"periph.io/x/devices/v3/ssd1306"
-	"periph.io/x/host/v3"
 )
 
 const (
-	width      = 128
-	height     = 64
 	charWidth  = 8
 	charHeight = 16
-	maxChars   = 16 // Maximum characters per line (width / charWidth)
-	maxLines   = 4  // Maximum lines (height / charHeight)
 )
 
-// Display handles the OLED screen operations
-type Display struct {
-	dev           *ssd1306.Dev
-	lines         [maxLines]string
-	prevLines     [maxLines]string      // Track previous state for each line
-	charPositions [maxLines][maxChars]bool // Track which character positions changed
-	i2cBus        i2c.BusCloser
-	img           *image.RGBA // Keep a buffer of the current display state
-	initialized   bool        // Track if the display has been fully drawn once
+// Kind selects which physical panel a Display drives.
+type Kind string
+
+const (
+	KindSSD1306  Kind = "ssd1306"
+	KindEPD2in13 Kind = "epd_2in13"
+)
+
+// Config selects and configures the physical panel to use.
+type Config struct {
+	Kind Kind
 }
 
-// New creates and initializes a new Display
-func New() (*Display, error) {
-	// Initialize periph.io
-	if _, err := host.Init(); err != nil {
-		return nil, fmt.Errorf("failed to initialize periph: %v", err)
-	}
+// Display is anything that can show the sensor lines produced by the
+// sysinfo providers. WriteLine/Clear only update in-memory state; Update
+// pushes the accumulated changes to the panel.
+type Display interface {
+	// WriteLine sets the content of a line, truncating/padding to fit.
+	WriteLine(line int, content string)
+	// Clear blanks every line.
+	Clear()
+	// Update pushes any pending changes to the panel.
+	Update() error
+	// Close releases the underlying bus/SPI port and any GPIO pins.
+	Close()
+	// Size reports the character grid available for WriteLine.
+	Size() (cols, rows int)
+}
 
-	// Open I2C bus
-	bus, err := i2creg.Open("")
-	if err != nil {
-		return nil, fmt.Errorf("failed to open I2C bus: %v", err)
+// Transport is the pixel sink a Display renders its character grid onto. It
+// abstracts over the physical link to the panel (I2C for SSD1306, SPI+GPIO
+// for e-paper) so the character-grid logic below doesn't need to know how
+// pixels reach the glass.
+type Transport interface {
+	// Bounds returns the panel resolution in pixels.
+	Bounds() image.Rectangle
+	// Draw pushes the pixels of img within rect to the panel at that same
+	// position. Implementations may buffer and coalesce updates (e.g.
+	// e-paper, which cannot cheaply repaint a single glyph).
+	Draw(rect image.Rectangle, img *image.RGBA) error
+	// Close releases the underlying bus/SPI port and any GPIO pins.
+	Close() error
+}
+
+// FullRefresher is implemented by Displays backed by a transport where
+// periodic full repaints matter (e-paper accumulates ghosting under partial
+// refreshes). Callers can type-assert a Display to this to force one
+// on demand, in addition to the transport's own periodic full refresh.
+type FullRefresher interface {
+	FullRefresh() error
+}
+
+// New creates a Display for the panel named by cfg.Kind. An empty Kind
+// defaults to the original SSD1306 OLED, so existing deployments keep
+// working unchanged.
+func New(cfg Config) (Display, error) {
+	switch cfg.Kind {
+	case "", KindSSD1306:
+		t, err := newSSD1306Transport(128, 64)
+		if err != nil {
+			return nil, err
+		}
+		return newCharGridDisplay(t), nil
+	case KindEPD2in13:
+		t, err := newEPDTransport()
+		if err != nil {
+			return nil, err
+		}
+		return newCharGridDisplay(t), nil
+	default:
+		return nil, fmt.Errorf("unknown display kind %q", cfg.Kind)
 	}
+}
+
+// charGridDisplay implements Display on top of any Transport by keeping a
+// grid of fixed-width character cells, tracking which cells changed since
+// the last Update, and pushing only the smallest rectangle covering the
+// dirty cells.
+type charGridDisplay struct {
+	transport     Transport
+	cols, rows    int
+	lines         []string
+	charPositions [][]bool
+	img           *image.RGBA
+	initialized   bool
+}
 
-	// Initialize the SSD1306 display (default address 0x3C)
-	dev, err := ssd1306.NewI2C(bus, &ssd1306.Opts{
-		W:       width,
-		H:       height,
-		Rotated: false,
-	})
-	if err != nil {
-		bus.Close()
-		return nil, fmt.Errorf("failed to initialize SSD1306: %v", err)
+func newCharGridDisplay(t Transport) *charGridDisplay {
+	bounds := t.Bounds()
+	cols := bounds.Dx() / charWidth
+	rows := bounds.Dy() / charHeight
+
+	charPositions := make([][]bool, rows)
+	for i := range charPositions {
+		charPositions[i] = make([]bool, cols)
 	}
 
-	// Create empty image buffer
-	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	img := image.NewRGBA(bounds)
 	draw.Draw(img, img.Bounds(), &image.Uniform{color.Black}, image.Point{}, draw.Src)
 
-	return &Display{
-		dev:         dev,
-		i2cBus:      bus,
-		img:         img,
-		initialized: false,
-	}, nil
+	return &charGridDisplay{
+		transport:     t,
+		cols:          cols,
+		rows:          rows,
+		lines:         make([]string, rows),
+		charPositions: charPositions,
+		img:           img,
+	}
+}
+
+// Size reports the character grid available for WriteLine.
+func (d *charGridDisplay) Size() (cols, rows int) {
+	return d.cols, d.rows
 }
 
-// WriteLine sets the content for a specific line
-func (d *Display) WriteLine(line int, content string) {
-	if line < 0 || line >= maxLines {
+// WriteLine sets the content for a specific line.
+func (d *charGridDisplay) WriteLine(line int, content string) {
+	if line < 0 || line >= d.rows {
 		return
 	}
 
-	// Truncate content if needed
-	if len(content) > maxChars {
-		content = content[:maxChars]
+	if len(content) > d.cols {
+		content = content[:d.cols]
 	}
-	
-	// If line content has changed, update and mark characters that need redrawing
-	if content != d.lines[line] {
-		// Compare each character position to determine which ones changed
-		for i := 0; i < maxChars; i++ {
-			var prevChar, newChar byte
-			
-			if i < len(d.lines[line]) {
-				prevChar = d.lines[line][i]
-			} else {
-				prevChar = ' '
-			}
-			
-			if i < len(content) {
-				newChar = content[i]
-			} else {
-				newChar = ' '
-			}
-			
-			// Mark character position for update if character has changed
-			d.charPositions[line][i] = prevChar != newChar
+
+	if content == d.lines[line] {
+		return
+	}
+
+	for i := 0; i < d.cols; i++ {
+		var prevChar, newChar byte
+		if i < len(d.lines[line]) {
+			prevChar = d.lines[line][i]
+		} else {
+			prevChar = ' '
+		}
+		if i < len(content) {
+			newChar = content[i]
+		} else {
+			newChar = ' '
+		}
+		if prevChar != newChar {
+			d.charPositions[line][i] = true
 		}
-		
-		// Update the line content
-		d.lines[line] = content
 	}
+
+	d.lines[line] = content
 }
 
-// Clear clears all lines on the display
-func (d *Display) Clear() {
+// Clear blanks every line.
+func (d *charGridDisplay) Clear() {
 	for i := range d.lines {
 		if d.lines[i] != "" {
 			d.lines[i] = ""
-			// Mark all characters as needing update
 			for j := range d.charPositions[i] {
 				d.charPositions[i][j] = true
 			}
 		}
 	}
-	
-	// Only perform a full clear if we need to
-	if d.initialized {
-		d.redrawChangedChars()
-	} else {
-		// If not initialized, do a full clear
-		draw.Draw(d.img, d.img.Bounds(), &image.Uniform{color.Black}, image.Point{}, draw.Src)
-		d.dev.Draw(d.img.Bounds(), d.img, image.Point{})
-	}
 }
 
-// Update refreshes the display with current line contents
-func (d *Display) Update() error {
-	if d.dev == nil {
-		return fmt.Errorf("display not initialized")
-	}
-	
+// Update pushes the smallest rectangle covering every dirty cell to the
+// transport, or does a full draw the first time it's called.
+func (d *charGridDisplay) Update() error {
 	if !d.initialized {
-		// First time: full draw of everything
-		if err := d.fullDraw(); err != nil {
+		d.markAllDirty()
+		if err := d.redraw(d.img.Bounds()); err != nil {
 			return err
 		}
 		d.initialized = true
-		// Save current state as previous
-		for i := range d.lines {
-			d.prevLines[i] = d.lines[i]
-		}
 		return nil
 	}
-	
-	// Check if any character has changed
-	needsUpdate := false
-	for _, line := range d.charPositions {
-		for _, changed := range line {
-			if changed {
-				needsUpdate = true
-				break
-			}
+
+	dirty, ok := d.dirtyBounds()
+	if !ok {
+		return nil // nothing changed, no need to push anything
+	}
+	return d.redraw(dirty)
+}
+
+func (d *charGridDisplay) markAllDirty() {
+	for i := range d.charPositions {
+		for j := range d.charPositions[i] {
+			d.charPositions[i][j] = true
 		}
-		if needsUpdate {
-			break
+	}
+}
+
+// dirtyBounds returns the smallest pixel rectangle covering every cell
+// marked dirty, or ok=false if nothing changed.
+func (d *charGridDisplay) dirtyBounds() (image.Rectangle, bool) {
+	minCol, minRow := d.cols, d.rows
+	maxCol, maxRow := -1, -1
+
+	for row, cells := range d.charPositions {
+		for col, dirty := range cells {
+			if !dirty {
+				continue
+			}
+			if col < minCol {
+				minCol = col
+			}
+			if col > maxCol {
+				maxCol = col
+			}
+			if row < minRow {
+				minRow = row
+			}
+			if row > maxRow {
+				maxRow = row
+			}
 		}
 	}
-	
-	if !needsUpdate {
-		return nil // Nothing changed, no need to update
+
+	if maxCol < 0 {
+		return image.Rectangle{}, false
 	}
-	
-	// Update only the changed characters
-	return d.redrawChangedChars()
+
+	return image.Rect(
+		minCol*charWidth, minRow*charHeight,
+		(maxCol+1)*charWidth, (maxRow+1)*charHeight,
+	), true
 }
 
-// fullDraw performs a complete redraw of the display
-func (d *Display) fullDraw() error {
-	// Create a black image buffer
-	draw.Draw(d.img, d.img.Bounds(), &image.Uniform{color.Black}, image.Point{}, draw.Src)
+// redraw renders every line that falls within rect and pushes rect to the
+// transport, then clears the dirty flags it covers.
+func (d *charGridDisplay) redraw(rect image.Rectangle) error {
+	draw.Draw(d.img, rect, &image.Uniform{color.Black}, rect.Min, draw.Src)
 
-	// Use a basic font to draw each line
 	face := basicfont.Face7x13
-	for i, line := range d.lines {
-		if line == "" {
+	for row, line := range d.lines {
+		y := row * charHeight
+		if y+charHeight <= rect.Min.Y || y >= rect.Max.Y {
 			continue
 		}
-		dot := fixed.Point26_6{
-			X: fixed.I(0),
-			Y: fixed.I((i+1)*charHeight - 3), // Adjust vertical spacing
+		if line == "" {
+			continue
 		}
 		drawer := &font.Drawer{
 			Dst:  d.img,
 			Src:  image.White,
 			Face: face,
-			Dot:  dot,
+			Dot: fixed.Point26_6{
+				X: fixed.I(0),
+				Y: fixed.I(y + charHeight - 3),
+			},
 		}
 		drawer.DrawString(strings.TrimSpace(line))
 	}
 
-	// Push image to OLED display
-	return d.dev.Draw(d.img.Bounds(), d.img, image.Point{})
-}
+	if err := d.transport.Draw(rect, d.img); err != nil {
+		return err
+	}
 
-// redrawChangedChars updates only the characters that have changed
-func (d *Display) redrawChangedChars() error {
-	// Use a basic font 
-	face := basicfont.Face7x13
-	
-	// Check each position that needs updating
-	for lineIdx, line := range d.charPositions {
-		for charIdx, needsUpdate := range line {
-			if !needsUpdate {
-				continue
-			}
-			
-			// Calculate the position for this character
-			x := charIdx * charWidth
-			y := lineIdx * charHeight
-			
-			// Create a small rect for just this character
-			charRect := image.Rect(x, y, x+charWidth, y+charHeight)
-			
-			// Clear this character position
-			draw.Draw(d.img, charRect, &image.Uniform{color.Black}, image.Point{}, draw.Src)
-			
-			// If there's a character to draw at this position, draw it
-			if lineIdx < len(d.lines) && charIdx < len(d.lines[lineIdx]) {
-				char := string(d.lines[lineIdx][charIdx])
-				dot := fixed.Point26_6{
-					X: fixed.I(x),
-					Y: fixed.I(y + charHeight - 3), // Adjust vertical spacing
-				}
-				drawer := &font.Drawer{
-					Dst:  d.img,
-					Src:  image.White,
-					Face: face,
-					Dot:  dot,
-				}
-				drawer.DrawString(char)
-			}
-			
-			// Update only this part of the display
-			if err := d.dev.Draw(charRect, d.img, image.Point{X: x, Y: y}); err != nil {
-				return err
-			}
-			
-			// Mark as updated
-			d.charPositions[lineIdx][charIdx] = false
+	for row := rect.Min.Y / charHeight; row < rect.Max.Y/charHeight; row++ {
+		for col := rect.Min.X / charWidth; col < rect.Max.X/charWidth; col++ {
+			d.charPositions[row][col] = false
 		}
 	}
-	
-	// Save current state as previous
-	for i := range d.lines {
-		d.prevLines[i] = d.lines[i]
-	}
-	
 	return nil
 }
 
-// Close shuts down the display properly
-func (d *Display) Close() {
-	if d.dev != nil {
-		d.dev.Halt()
-	}
-	if d.i2cBus != nil {
-		d.i2cBus.Close()
+// Close releases the underlying bus/SPI port and any GPIO pins.
+func (d *charGridDisplay) Close() {
+	d.transport.Close()
+}
+
+// FullRefresh forces a full repaint on transports that support it (see
+// FullRefresher); it's a no-op otherwise.
+func (d *charGridDisplay) FullRefresh() error {
+	if r, ok := d.transport.(FullRefresher); ok {
+		return r.FullRefresh()
 	}
+	return nil
 }
@@ -0,0 +1,281 @@
+package display
+
+import (
+	"fmt"
+	"image"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpioreg"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+	"periph.io/x/conn/v3/spi/spireg"
+	"periph.io/x/host/v3"
+)
+
+// Waveshare 2.13" V2 panel geometry and SSD1680-family command set.
+const (
+	epdWidth  = 122
+	epdHeight = 250
+
+	epdCmdDriverOutputControl   = 0x01
+	epdCmdDataEntryMode         = 0x11
+	epdCmdSetRAMXAddress        = 0x44
+	epdCmdSetRAMYAddress        = 0x45
+	epdCmdSetRAMXCounter        = 0x4E
+	epdCmdSetRAMYCounter        = 0x4F
+	epdCmdBorderWaveform        = 0x3C
+	epdCmdTempSensorControl     = 0x18
+	epdCmdWriteRAMBW            = 0x24
+	epdCmdDisplayUpdateControl2 = 0x22
+	epdCmdMasterActivation      = 0x20
+
+	epdUpdateModeFull    = 0xF7
+	epdUpdateModePartial = 0xFF
+
+	// epdFullRefreshEvery bounds how many partial refreshes run before a
+	// full refresh is forced. E-paper only updates the pixels that changed
+	// under a partial refresh, so residual charge builds up into visible
+	// ghosting if it never gets a full repaint.
+	epdFullRefreshEvery = 20
+)
+
+// epdTransport drives a Waveshare-style e-paper panel over SPI, with
+// RST/DC/CS wired as GPIO outputs and BUSY as a GPIO input. Because
+// repainting a single glyph is expensive on e-paper, every Draw is a
+// partial refresh of the requested rectangle, with a full refresh forced
+// every epdFullRefreshEvery calls (or on demand via FullRefresh) to clear
+// ghosting.
+type epdTransport struct {
+	port spi.PortCloser
+	conn spi.Conn
+	rst  gpio.PinOut
+	dc   gpio.PinOut
+	cs   gpio.PinOut
+	busy gpio.PinIn
+
+	frame        *image.RGBA
+	partialCount int
+}
+
+func newEPDTransport() (*epdTransport, error) {
+	if _, err := host.Init(); err != nil {
+		return nil, fmt.Errorf("failed to initialize periph: %v", err)
+	}
+
+	port, err := spireg.Open("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SPI port: %v", err)
+	}
+	conn, err := port.Connect(4*physic.MegaHertz, spi.Mode0, 8)
+	if err != nil {
+		port.Close()
+		return nil, fmt.Errorf("failed to configure SPI: %v", err)
+	}
+
+	rst := gpioreg.ByName("GPIO17")
+	dc := gpioreg.ByName("GPIO25")
+	cs := gpioreg.ByName("GPIO8")
+	busy := gpioreg.ByName("GPIO24")
+	if rst == nil || dc == nil || cs == nil || busy == nil {
+		port.Close()
+		return nil, fmt.Errorf("failed to resolve EPD RST/DC/CS/BUSY GPIO pins")
+	}
+	if err := rst.Out(gpio.High); err != nil {
+		port.Close()
+		return nil, fmt.Errorf("failed to set RST pin: %v", err)
+	}
+	if err := dc.Out(gpio.High); err != nil {
+		port.Close()
+		return nil, fmt.Errorf("failed to set DC pin: %v", err)
+	}
+	if err := cs.Out(gpio.High); err != nil {
+		port.Close()
+		return nil, fmt.Errorf("failed to set CS pin: %v", err)
+	}
+	if err := busy.In(gpio.PullNoChange, gpio.NoEdge); err != nil {
+		port.Close()
+		return nil, fmt.Errorf("failed to set BUSY pin: %v", err)
+	}
+
+	t := &epdTransport{port: port, conn: conn, rst: rst, dc: dc, cs: cs, busy: busy}
+	if err := t.init(); err != nil {
+		port.Close()
+		return nil, err
+	}
+	return t, nil
+}
+
+// reset pulses RST to bring the panel controller out of reset.
+func (t *epdTransport) reset() {
+	t.rst.Out(gpio.High)
+	time.Sleep(200 * time.Millisecond)
+	t.rst.Out(gpio.Low)
+	time.Sleep(5 * time.Millisecond)
+	t.rst.Out(gpio.High)
+	time.Sleep(200 * time.Millisecond)
+}
+
+func (t *epdTransport) sendCommand(cmd byte) error {
+	t.dc.Out(gpio.Low)
+	t.cs.Out(gpio.Low)
+	defer t.cs.Out(gpio.High)
+	return t.conn.Tx([]byte{cmd}, nil)
+}
+
+func (t *epdTransport) sendData(data []byte) error {
+	t.dc.Out(gpio.High)
+	t.cs.Out(gpio.Low)
+	defer t.cs.Out(gpio.High)
+	return t.conn.Tx(data, nil)
+}
+
+func (t *epdTransport) waitUntilIdle() {
+	for t.busy.Read() == gpio.High {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func (t *epdTransport) init() error {
+	t.reset()
+	t.waitUntilIdle()
+
+	if err := t.sendCommand(epdCmdDriverOutputControl); err != nil {
+		return err
+	}
+	if err := t.sendData([]byte{byte(epdHeight - 1), byte((epdHeight - 1) >> 8), 0x00}); err != nil {
+		return err
+	}
+	if err := t.sendCommand(epdCmdDataEntryMode); err != nil {
+		return err
+	}
+	if err := t.sendData([]byte{0x03}); err != nil {
+		return err
+	}
+	if err := t.sendCommand(epdCmdBorderWaveform); err != nil {
+		return err
+	}
+	if err := t.sendData([]byte{0x05}); err != nil {
+		return err
+	}
+	if err := t.sendCommand(epdCmdTempSensorControl); err != nil {
+		return err
+	}
+	if err := t.sendData([]byte{0x80}); err != nil {
+		return err
+	}
+	t.waitUntilIdle()
+	return nil
+}
+
+func (t *epdTransport) Bounds() image.Rectangle {
+	return image.Rect(0, 0, epdWidth, epdHeight)
+}
+
+// Draw writes rect of img into the panel's RAM and activates a partial
+// refresh, forcing a full refresh instead every epdFullRefreshEvery calls.
+func (t *epdTransport) Draw(rect image.Rectangle, img *image.RGBA) error {
+	t.frame = img
+	full := t.partialCount == 0
+	if err := t.paint(rect, img, full); err != nil {
+		return err
+	}
+	t.partialCount = (t.partialCount + 1) % epdFullRefreshEvery
+	return nil
+}
+
+// FullRefresh forces a full repaint of the last frame drawn, clearing any
+// ghosting accumulated by partial refreshes, and resets the counter.
+func (t *epdTransport) FullRefresh() error {
+	if t.frame == nil {
+		return nil
+	}
+	if err := t.paint(t.Bounds(), t.frame, true); err != nil {
+		return err
+	}
+	t.partialCount = 0
+	return nil
+}
+
+func (t *epdTransport) paint(rect image.Rectangle, img *image.RGBA, full bool) error {
+	if err := t.setWindow(rect); err != nil {
+		return err
+	}
+	if err := t.sendCommand(epdCmdWriteRAMBW); err != nil {
+		return err
+	}
+	if err := t.sendData(rasterize1bpp(img, rect)); err != nil {
+		return err
+	}
+
+	mode := byte(epdUpdateModePartial)
+	if full {
+		mode = epdUpdateModeFull
+	}
+	if err := t.sendCommand(epdCmdDisplayUpdateControl2); err != nil {
+		return err
+	}
+	if err := t.sendData([]byte{mode}); err != nil {
+		return err
+	}
+	if err := t.sendCommand(epdCmdMasterActivation); err != nil {
+		return err
+	}
+	t.waitUntilIdle()
+	return nil
+}
+
+// setWindow positions the panel's RAM X/Y address window and counters over
+// rect so the following WriteRAM only touches those bytes.
+func (t *epdTransport) setWindow(rect image.Rectangle) error {
+	xStart, xEnd := byte(rect.Min.X/8), byte((rect.Max.X-1)/8)
+	yStart, yEnd := rect.Min.Y, rect.Max.Y-1
+
+	if err := t.sendCommand(epdCmdSetRAMXAddress); err != nil {
+		return err
+	}
+	if err := t.sendData([]byte{xStart, xEnd}); err != nil {
+		return err
+	}
+	if err := t.sendCommand(epdCmdSetRAMYAddress); err != nil {
+		return err
+	}
+	if err := t.sendData([]byte{byte(yStart), byte(yStart >> 8), byte(yEnd), byte(yEnd >> 8)}); err != nil {
+		return err
+	}
+	if err := t.sendCommand(epdCmdSetRAMXCounter); err != nil {
+		return err
+	}
+	if err := t.sendData([]byte{xStart}); err != nil {
+		return err
+	}
+	if err := t.sendCommand(epdCmdSetRAMYCounter); err != nil {
+		return err
+	}
+	return t.sendData([]byte{byte(yStart), byte(yStart >> 8)})
+}
+
+// rasterize1bpp packs rect of img into the panel's 1-bit-per-pixel, 8
+// pixels-per-byte RAM layout, treating any non-black pixel as set.
+func rasterize1bpp(img *image.RGBA, rect image.Rectangle) []byte {
+	bytesPerRow := (rect.Dx() + 7) / 8
+	buf := make([]byte, bytesPerRow*rect.Dy())
+
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			if r == 0 && g == 0 && b == 0 {
+				continue
+			}
+			row := y - rect.Min.Y
+			col := x - rect.Min.X
+			buf[row*bytesPerRow+col/8] |= 0x80 >> uint(col%8)
+		}
+	}
+	return buf
+}
+
+func (t *epdTransport) Close() error {
+	t.rst.Out(gpio.Low)
+	return t.port.Close()
+}
@@ -0,0 +1,55 @@
+package display
+
+import (
+	"fmt"
+	"image"
+
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/i2c/i2creg"
+	"periph.io/x/devices/v3/ssd1306"
+	"periph.io/x/host/v3"
+)
+
+// ssd1306Transport drives an SSD1306 OLED panel over I2C.
+type ssd1306Transport struct {
+	bus i2c.BusCloser
+	dev *ssd1306.Dev
+}
+
+func newSSD1306Transport(w, h int) (*ssd1306Transport, error) {
+	if _, err := host.Init(); err != nil {
+		return nil, fmt.Errorf("failed to initialize periph: %v", err)
+	}
+
+	bus, err := i2creg.Open("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open I2C bus: %v", err)
+	}
+
+	dev, err := ssd1306.NewI2C(bus, &ssd1306.Opts{
+		W:       w,
+		H:       h,
+		Rotated: false,
+	})
+	if err != nil {
+		bus.Close()
+		return nil, fmt.Errorf("failed to initialize SSD1306: %v", err)
+	}
+
+	return &ssd1306Transport{bus: bus, dev: dev}, nil
+}
+
+func (t *ssd1306Transport) Bounds() image.Rectangle {
+	return t.dev.Bounds()
+}
+
+func (t *ssd1306Transport) Draw(rect image.Rectangle, img *image.RGBA) error {
+	return t.dev.Draw(rect, img, rect.Min)
+}
+
+func (t *ssd1306Transport) Close() error {
+	if err := t.dev.Halt(); err != nil {
+		return err
+	}
+	return t.bus.Close()
+}
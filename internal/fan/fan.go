@@ -0,0 +1,184 @@
+// Package fan drives one or more GPIO PWM fans as a closed-loop controller
+// over the temperature readings produced by internal/sysinfo/temp.
+package fan
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpioreg"
+	"periph.io/x/conn/v3/physic"
+
+	"github.com/snehithgit/pi_oled_info/internal/sysinfo/registry"
+	"github.com/snehithgit/pi_oled_info/internal/sysinfo/temp"
+)
+
+// pwmFrequency is used for every fan; most 4-pin PC/Pi-style fans expect a
+// PWM input around 25kHz.
+const pwmFrequency = 25 * physic.KiloHertz
+
+// tickInterval is how often the controller re-reads temperature and
+// updates duty.
+const tickInterval = time.Second
+
+// Config configures one PWM-driven fan as a closed-loop controller over
+// temperature.
+type Config struct {
+	// Pin is the GPIO pin name/number driving the fan, resolved via gpioreg.
+	Pin string
+	// MinTempC and MaxTempC bound the linear interpolation range.
+	MinTempC, MaxTempC float64
+	// MinDuty and MaxDuty bound the duty cycle sent to the pin.
+	MinDuty, MaxDuty gpio.Duty
+	// Hysteresis keeps the duty from oscillating: the duty only changes once
+	// the temperature has moved by at least Hysteresis since the last
+	// reading the controller acted on (whether or not that reading changed
+	// the duty).
+	Hysteresis float64
+}
+
+// fan is a single closed-loop PWM fan.
+type fan struct {
+	cfg Config
+	pin gpio.PinIO
+
+	duty       gpio.Duty
+	lastTemp   float64
+	hasReading bool
+}
+
+func newFan(cfg Config) (*fan, error) {
+	pin := gpioreg.ByName(cfg.Pin)
+	if pin == nil {
+		return nil, fmt.Errorf("unknown GPIO pin %q", cfg.Pin)
+	}
+	return &fan{cfg: cfg, pin: pin}, nil
+}
+
+// tick applies the duty for celsius, honoring the hysteresis band.
+func (f *fan) tick(celsius float64) {
+	if f.hasReading && math.Abs(celsius-f.lastTemp) < f.cfg.Hysteresis {
+		return
+	}
+	f.lastTemp = celsius
+	f.hasReading = true
+
+	duty := f.dutyFor(celsius)
+	if duty == f.duty {
+		return
+	}
+	if err := f.pin.PWM(duty, pwmFrequency); err == nil {
+		f.duty = duty
+	}
+}
+
+// dutyFor linearly interpolates celsius between MinTempC/MaxTempC into
+// MinDuty/MaxDuty, clamped to that range.
+func (f *fan) dutyFor(celsius float64) gpio.Duty {
+	cfg := f.cfg
+	if celsius <= cfg.MinTempC {
+		return cfg.MinDuty
+	}
+	if celsius >= cfg.MaxTempC {
+		return cfg.MaxDuty
+	}
+
+	frac := (celsius - cfg.MinTempC) / (cfg.MaxTempC - cfg.MinTempC)
+	return cfg.MinDuty + gpio.Duty(frac*float64(cfg.MaxDuty-cfg.MinDuty))
+}
+
+func (f *fan) halt() {
+	if err := f.pin.PWM(0, pwmFrequency); err == nil {
+		f.duty = 0
+	}
+}
+
+// Controller runs a closed-loop PWM fan controller for one or more fans,
+// and registers itself as the "fan" provider reporting their current duty.
+type Controller struct {
+	mu   sync.Mutex
+	fans []*fan
+}
+
+// NewController resolves every cfg's pin and registers the controller as
+// the "fan" provider. Call Run in its own goroutine to start the control
+// loop.
+func NewController(cfgs []Config) (*Controller, error) {
+	fans := make([]*fan, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		f, err := newFan(cfg)
+		if err != nil {
+			return nil, err
+		}
+		fans = append(fans, f)
+	}
+
+	c := &Controller{fans: fans}
+	registry.Register(c)
+	return c, nil
+}
+
+// Run re-reads temperature and updates every fan's duty once per
+// tickInterval until stop is closed.
+func (c *Controller) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.tick()
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (c *Controller) tick() {
+	sensors, err := temp.Sensors()
+	if err != nil {
+		return
+	}
+	hottest, ok := temp.Highest(sensors)
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, f := range c.fans {
+		f.tick(hottest.Celsius)
+	}
+}
+
+// Halt sets every fan's duty to 0, e.g. on shutdown.
+func (c *Controller) Halt() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, f := range c.fans {
+		f.halt()
+	}
+}
+
+// Name implements registry.Provider.
+func (c *Controller) Name() string { return "fan" }
+
+// Get implements registry.Provider, reporting every fan's current duty.
+func (c *Controller) Get() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.fans) == 0 {
+		return "", fmt.Errorf("no fans configured")
+	}
+
+	duties := make([]string, len(c.fans))
+	for i, f := range c.fans {
+		duties[i] = f.duty.String()
+	}
+	return "Fan: " + strings.Join(duties, " "), nil
+}
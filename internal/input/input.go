@@ -0,0 +1,131 @@
+// Package input watches GPIO buttons and turns their presses into
+// page-navigation events for the display's page cycling.
+package input
+
+import (
+	"fmt"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpioreg"
+)
+
+// Event is a page-navigation action derived from a button press.
+type Event int
+
+const (
+	// PageNext advances to the next page, on a short press of Config.Pin.
+	PageNext Event = iota
+	// PagePrev moves back to the previous page, on a short press of
+	// Config.PrevPin.
+	PagePrev
+	// PageSelect is emitted on a long press of either button.
+	PageSelect
+)
+
+const (
+	// debounce is how long a press must settle before its matching release
+	// edge is trusted.
+	debounce = 50 * time.Millisecond
+	// longPress is the hold duration that turns a release into PageSelect
+	// instead of PageNext/PagePrev.
+	longPress = 500 * time.Millisecond
+	// edgePollInterval bounds each WaitForEdge call so watchPin notices
+	// stop being closed even while the button sits idle.
+	edgePollInterval = 200 * time.Millisecond
+)
+
+// Config wires up the page-cycling button(s). Pin is required and drives
+// PageNext/PageSelect; PrevPin is optional and, if set, drives
+// PagePrev/PageSelect on a second button.
+type Config struct {
+	Pin     string
+	PrevPin string
+}
+
+// DefaultConfig uses BCM 17, the conventional choice for a single headless
+// "next page" button.
+var DefaultConfig = Config{Pin: "GPIO17"}
+
+// Watch opens the configured button(s) and sends page events to events
+// until stop is closed. Each button is watched in its own goroutine.
+func Watch(cfg Config, events chan<- Event, stop <-chan struct{}) error {
+	pin, err := openButton(cfg.Pin)
+	if err != nil {
+		return err
+	}
+	go watchPin(pin, PageNext, events, stop)
+
+	if cfg.PrevPin != "" {
+		prevPin, err := openButton(cfg.PrevPin)
+		if err != nil {
+			return err
+		}
+		go watchPin(prevPin, PagePrev, events, stop)
+	}
+	return nil
+}
+
+func openButton(name string) (gpio.PinIn, error) {
+	pin := gpioreg.ByName(name)
+	if pin == nil {
+		return nil, fmt.Errorf("unknown GPIO pin %q", name)
+	}
+	if err := pin.In(gpio.PullUp, gpio.BothEdges); err != nil {
+		return nil, fmt.Errorf("failed to configure pin %q as input: %v", name, err)
+	}
+	return pin, nil
+}
+
+// watchPin treats pin as an active-low button (pulled up, shorted to
+// ground on press), waiting for the falling edge, debouncing, then timing
+// how long it stays low to pick between shortEvent and PageSelect.
+func watchPin(pin gpio.PinIn, shortEvent Event, events chan<- Event, stop <-chan struct{}) {
+	for {
+		if !waitForEdge(pin, stop) {
+			return
+		}
+		if pin.Read() != gpio.Low {
+			continue // only a press (falling edge) starts a timed hold
+		}
+
+		time.Sleep(debounce)
+		if pin.Read() != gpio.Low {
+			continue // was noise, not a real press
+		}
+		pressedAt := time.Now()
+
+		if !waitForEdge(pin, stop) {
+			return
+		}
+		held := time.Since(pressedAt)
+
+		event := shortEvent
+		if held >= longPress {
+			event = PageSelect
+		}
+
+		select {
+		case events <- event:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// waitForEdge polls pin in bounded edgePollInterval steps instead of
+// blocking indefinitely, checking stop between each poll, so the watcher
+// goroutine actually exits once stop is closed even if the button never
+// fires another edge.
+func waitForEdge(pin gpio.PinIn, stop <-chan struct{}) bool {
+	for {
+		select {
+		case <-stop:
+			return false
+		default:
+		}
+		if pin.WaitForEdge(edgePollInterval) {
+			return true
+		}
+	}
+}
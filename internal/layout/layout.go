@@ -0,0 +1,100 @@
+// Package layout parses the small text spec (à la gotop) that describes
+// what appears on each display line and how the line's width is shared
+// between providers.
+package layout
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ProviderSpec names one provider to render within a line and the
+// proportion of the line's width it should get relative to its siblings.
+type ProviderSpec struct {
+	ProviderName string
+	Weight       int
+}
+
+// LineSpec is the set of providers sharing a single display line, in
+// left-to-right order.
+type LineSpec []ProviderSpec
+
+// DefaultSpec reproduces the original hard-coded layout: one provider per
+// line, in the order IP, uptime, CPU, disk.
+var DefaultSpec = []LineSpec{
+	{{ProviderName: "ip", Weight: 1}},
+	{{ProviderName: "up", Weight: 1}},
+	{{ProviderName: "cpu", Weight: 1}},
+	{{ProviderName: "disk", Weight: 1}},
+}
+
+// Parse reads a layout spec, one display line per line of text. Each line
+// holds space-separated provider tokens of the form "name" or
+// "name/weight" (weight defaults to 1), e.g.:
+//
+//	ip
+//	up cpu/2
+//	disk
+//
+// meaning line 1 is the IP address at full width, line 2 splits its width
+// between uptime and CPU with CPU getting 2/3 of it, and line 3 is disk.
+// Blank lines are ignored.
+func Parse(text string) ([]LineSpec, error) {
+	var spec []LineSpec
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var ls LineSpec
+		for _, tok := range strings.Fields(line) {
+			name, weightStr, hasWeight := strings.Cut(tok, "/")
+			weight := 1
+			if hasWeight {
+				w, err := strconv.Atoi(weightStr)
+				if err != nil || w <= 0 {
+					return nil, fmt.Errorf("invalid weight in %q", tok)
+				}
+				weight = w
+			}
+			if name == "" {
+				return nil, fmt.Errorf("empty provider name in %q", line)
+			}
+			ls = append(ls, ProviderSpec{ProviderName: name, Weight: weight})
+		}
+		spec = append(spec, ls)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// Shares splits width among the providers on a line proportionally to
+// their weight, in order, with any leftover character going to the last
+// provider so the shares always sum to width.
+func (ls LineSpec) Shares(width int) []int {
+	totalWeight := 0
+	for _, p := range ls {
+		totalWeight += p.Weight
+	}
+	if totalWeight == 0 {
+		return make([]int, len(ls))
+	}
+
+	shares := make([]int, len(ls))
+	used := 0
+	for i, p := range ls {
+		if i == len(ls)-1 {
+			shares[i] = width - used
+			continue
+		}
+		shares[i] = width * p.Weight / totalWeight
+		used += shares[i]
+	}
+	return shares
+}
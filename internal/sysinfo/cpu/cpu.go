@@ -2,14 +2,24 @@ package cpu
 
 import (
 	"fmt"
-	"io/ioutil"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/shirou/gopsutil/cpu"
+
+	"github.com/snehithgit/pi_oled_info/internal/sysinfo/registry"
+	"github.com/snehithgit/pi_oled_info/internal/sysinfo/temp"
 )
 
+// provider self-registers this package under the name "cpu".
+type provider struct{}
+
+func (provider) Name() string         { return "cpu" }
+func (provider) Get() (string, error) { return Get() }
+
+func init() {
+	registry.Register(provider{})
+}
+
 // Get returns CPU usage percentage and temperature as a formatted string
 func Get() (string, error) {
 	// Get CPU usage percentage
@@ -23,31 +33,17 @@ func Get() (string, error) {
 		usage = percent[0]
 	}
 
-	// Get CPU temperature (Raspberry Pi specific)
-	temp, err := getCPUTemperature()
+	// Get CPU temperature
+	sensors, err := temp.Sensors()
 	if err != nil {
 		// If we can't get temperature, just show usage
 		return fmt.Sprintf("CPU: %.1f%%", usage), nil
 	}
-
-	// Format both usage and temperature
-	return fmt.Sprintf("CPU: %.1f%% %.1f°C", usage, temp), nil
-}
-
-// getCPUTemperature reads the CPU temperature from the Raspberry Pi thermal zone
-func getCPUTemperature() (float64, error) {
-	// Read from thermal zone
-	content, err := ioutil.ReadFile("/sys/class/thermal/thermal_zone0/temp")
-	if err != nil {
-		return 0, err
-	}
-
-	// Convert the temperature (in milliCelsius) to Celsius
-	tempStr := strings.TrimSpace(string(content))
-	tempMilliC, err := strconv.ParseInt(tempStr, 10, 64)
-	if err != nil {
-		return 0, err
+	hottest, ok := temp.Highest(sensors)
+	if !ok {
+		return fmt.Sprintf("CPU: %.1f%%", usage), nil
 	}
 
-	return float64(tempMilliC) / 1000.0, nil
+	// Format both usage and temperature
+	return fmt.Sprintf("CPU: %.1f%% %.1f°C", usage, hottest.Celsius), nil
 }
@@ -4,8 +4,20 @@ import (
 	"fmt"
 
 	"github.com/shirou/gopsutil/disk"
+
+	"github.com/snehithgit/pi_oled_info/internal/sysinfo/registry"
 )
 
+// provider self-registers this package under the name "disk".
+type provider struct{}
+
+func (provider) Name() string         { return "disk" }
+func (provider) Get() (string, error) { return Get() }
+
+func init() {
+	registry.Register(provider{})
+}
+
 // Get returns disk usage information as a formatted string
 func Get() (string, error) {
 	// Get usage statistics for the root filesystem
@@ -3,8 +3,20 @@ package ipaddr
 import (
 	"fmt"
 	"net"
+
+	"github.com/snehithgit/pi_oled_info/internal/sysinfo/registry"
 )
 
+// provider self-registers this package under the name "ip".
+type provider struct{}
+
+func (provider) Name() string         { return "ip" }
+func (provider) Get() (string, error) { return Get() }
+
+func init() {
+	registry.Register(provider{})
+}
+
 // Get returns the primary IP address as a formatted string
 func Get() (string, error) {
 	// Get the list of network interfaces
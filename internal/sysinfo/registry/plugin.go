@@ -0,0 +1,55 @@
+//go:build linux || darwin
+
+package registry
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"plugin"
+)
+
+// LoadPlugins opens every *.so in dir and calls its exported Register
+// symbol, expected to be a func(func(Provider)) that the plugin uses to
+// register its own providers. This lets users ship out-of-tree sensors
+// (Bluetooth, GPU, PiJuice battery, MQTT stats, etc.) without recompiling
+// the binary. A missing dir is not an error. A bad plugin is logged and
+// skipped rather than aborting the rest of the directory.
+func LoadPlugins(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".so" {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		p, err := plugin.Open(path)
+		if err != nil {
+			log.Printf("Error opening plugin %s: %v", path, err)
+			continue
+		}
+
+		sym, err := p.Lookup("Register")
+		if err != nil {
+			log.Printf("Plugin %s has no Register symbol: %v", path, err)
+			continue
+		}
+
+		register, ok := sym.(func(func(Provider)))
+		if !ok {
+			log.Printf("Plugin %s: Register has unexpected signature", path)
+			continue
+		}
+
+		register(Register)
+	}
+
+	return nil
+}
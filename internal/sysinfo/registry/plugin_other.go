@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package registry
+
+// LoadPlugins is a no-op on platforms where Go's plugin package isn't
+// supported (e.g. freebsd). Out-of-tree providers there must be compiled
+// directly into the binary.
+func LoadPlugins(dir string) error {
+	return nil
+}
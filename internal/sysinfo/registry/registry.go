@@ -0,0 +1,52 @@
+// Package registry is a shared lookup of sysinfo providers, so the layout
+// DSL can resolve a name like "cpu" to its rendered text without the rest
+// of the program needing to import every sysinfo package directly.
+package registry
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Provider renders one piece of system information as display-ready text.
+type Provider interface {
+	Name() string
+	Get() (string, error)
+}
+
+var (
+	mu        sync.RWMutex
+	providers = map[string]Provider{}
+)
+
+// Register adds a Provider under its Name(), overwriting any previous
+// provider of the same name. The built-in sysinfo packages call this from
+// their own init(), so merely importing them makes them available; plugins
+// loaded via LoadPlugins call it the same way.
+func Register(p Provider) {
+	mu.Lock()
+	defer mu.Unlock()
+	providers[p.Name()] = p
+}
+
+// Get looks up a provider by name and renders it.
+func Get(name string) (string, error) {
+	mu.RLock()
+	p, ok := providers[name]
+	mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("unknown provider %q", name)
+	}
+	return p.Get()
+}
+
+// Names returns every registered provider name.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,64 @@
+// Package temp reads hardware temperature sensors across platforms. Each
+// platform-specific file (temp_linux.go, temp_darwin.go, temp_freebsd.go)
+// implements Sensors() for its OS.
+package temp
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/snehithgit/pi_oled_info/internal/sysinfo/registry"
+)
+
+// Sensor is a single temperature reading, e.g. a Linux thermal zone, a
+// hwmon chip input, or an SMC key.
+type Sensor struct {
+	Label   string
+	Celsius float64
+}
+
+// Highest picks the hottest sensor, which is what a single-value caller
+// like cpu.Get usually wants.
+func Highest(sensors []Sensor) (Sensor, bool) {
+	var hottest Sensor
+	found := false
+	for _, s := range sensors {
+		if !found || s.Celsius > hottest.Celsius {
+			hottest = s
+			found = true
+		}
+	}
+	return hottest, found
+}
+
+// provider renders the registry's "temp" entry, cycling through whatever
+// Sensors() returns one sensor per call so a multi-zone board (e.g. a Pi 5)
+// gets to show all of its zones across successive display updates.
+type provider struct {
+	mu  sync.Mutex
+	idx int
+}
+
+func (p *provider) Name() string { return "temp" }
+
+func (p *provider) Get() (string, error) {
+	sensors, err := Sensors()
+	if err != nil {
+		return "", err
+	}
+	if len(sensors) == 0 {
+		return "", fmt.Errorf("no temperature sensors found")
+	}
+
+	p.mu.Lock()
+	i := p.idx % len(sensors)
+	p.idx++
+	p.mu.Unlock()
+
+	s := sensors[i]
+	return fmt.Sprintf("%s: %.1f°C", s.Label, s.Celsius), nil
+}
+
+func init() {
+	registry.Register(&provider{})
+}
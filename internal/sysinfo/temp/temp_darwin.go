@@ -0,0 +1,43 @@
+//go:build darwin
+
+package temp
+
+/*
+#cgo LDFLAGS: -framework IOKit -framework CoreFoundation
+#include <stdlib.h>
+#include "smc_darwin.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// smcKeys are the Apple System Management Controller keys that report CPU
+// (and, on some Macs, GPU) die temperature. CPU proximity ("TC0P") is the
+// most broadly available one across Intel Mac generations.
+var smcKeys = []string{"TC0P", "TC0D", "TG0D"}
+
+// Sensors reads CPU/GPU temperature straight from the SMC via the small
+// cgo shim in smc_darwin.c, since macOS has no equivalent of Linux's
+// thermal_zone sysfs files.
+func Sensors() ([]Sensor, error) {
+	var sensors []Sensor
+
+	for _, key := range smcKeys {
+		cKey := C.CString(key)
+		var celsius C.double
+		ok := C.smc_read_temperature(cKey, &celsius)
+		C.free(unsafe.Pointer(cKey))
+		if !ok {
+			continue
+		}
+		sensors = append(sensors, Sensor{Label: key, Celsius: float64(celsius)})
+	}
+
+	if len(sensors) == 0 {
+		return nil, fmt.Errorf("no SMC temperature keys could be read")
+	}
+	return sensors, nil
+}
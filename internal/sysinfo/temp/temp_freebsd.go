@@ -0,0 +1,45 @@
+//go:build freebsd
+
+package temp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// maxCPUSensors bounds how many dev.cpu.N.temperature sysctls are probed;
+// FreeBSD has no "list all cpus" sysctl, so we just stop at the first one
+// that doesn't exist.
+const maxCPUSensors = 64
+
+// Sensors reads each CPU core's temperature from the coretemp/amdtemp
+// sysctl, dev.cpu.N.temperature, exposed by FreeBSD as a string like
+// "45.0C".
+func Sensors() ([]Sensor, error) {
+	var sensors []Sensor
+
+	for i := 0; i < maxCPUSensors; i++ {
+		name := fmt.Sprintf("dev.cpu.%d.temperature", i)
+		raw, err := unix.Sysctl(name)
+		if err != nil {
+			if i == 0 {
+				continue // some systems start numbering at cpu.1
+			}
+			break
+		}
+
+		celsius, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(raw), "C"), 64)
+		if err != nil {
+			continue
+		}
+		sensors = append(sensors, Sensor{Label: fmt.Sprintf("cpu%d", i), Celsius: celsius})
+	}
+
+	if len(sensors) == 0 {
+		return nil, fmt.Errorf("no dev.cpu.N.temperature sysctls found")
+	}
+	return sensors, nil
+}
@@ -0,0 +1,70 @@
+//go:build linux
+
+package temp
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Sensors enumerates every Linux thermal zone under
+// /sys/class/thermal/thermal_zone*, labeled with its "type" file, plus
+// every hwmon temperature input under /sys/class/hwmon/hwmon*/temp*_input,
+// labeled with its sibling "_label" file when present. Covering both gives
+// meaningful readings on non-Pi hardware and on boards like the Pi 5 that
+// expose several thermal zones.
+func Sensors() ([]Sensor, error) {
+	var sensors []Sensor
+
+	zones, err := filepath.Glob("/sys/class/thermal/thermal_zone*/temp")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(zones)
+	for _, zonePath := range zones {
+		milliC, err := readMilliC(zonePath)
+		if err != nil {
+			continue
+		}
+		label := strings.TrimSuffix(filepath.Base(filepath.Dir(zonePath)), "/")
+		if t, err := os.ReadFile(filepath.Join(filepath.Dir(zonePath), "type")); err == nil {
+			label = strings.TrimSpace(string(t))
+		}
+		sensors = append(sensors, Sensor{Label: label, Celsius: float64(milliC) / 1000.0})
+	}
+
+	inputs, err := filepath.Glob("/sys/class/hwmon/hwmon*/temp*_input")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(inputs)
+	for _, inputPath := range inputs {
+		milliC, err := readMilliC(inputPath)
+		if err != nil {
+			continue
+		}
+		label := strings.TrimSuffix(filepath.Base(inputPath), "_input")
+		labelPath := strings.TrimSuffix(inputPath, "_input") + "_label"
+		if l, err := os.ReadFile(labelPath); err == nil {
+			label = strings.TrimSpace(string(l))
+		}
+		sensors = append(sensors, Sensor{Label: label, Celsius: float64(milliC) / 1000.0})
+	}
+
+	if len(sensors) == 0 {
+		return nil, fmt.Errorf("no thermal_zone or hwmon temperature sensors found")
+	}
+	return sensors, nil
+}
+
+func readMilliC(path string) (int64, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+}
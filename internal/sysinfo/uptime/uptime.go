@@ -6,8 +6,20 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/snehithgit/pi_oled_info/internal/sysinfo/registry"
 )
 
+// provider self-registers this package under the name "up".
+type provider struct{}
+
+func (provider) Name() string         { return "up" }
+func (provider) Get() (string, error) { return Get() }
+
+func init() {
+	registry.Register(provider{})
+}
+
 // Get returns the system uptime as a formatted string
 func Get() (string, error) {
 	// Read uptime from proc filesystem